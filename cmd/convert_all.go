@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	template "github.com/openshift/api/template/v1"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	tplDirDefault       = "."
+	tplDirUsage         = "Path to a directory of OpenShift Templates, walked recursively"
+	namespaceFilterDesc = "Only convert objects whose metadata.namespace equals this value"
+	labelSelectorDesc   = "Only convert objects matching this label selector (e.g. 'app=foo,tier!=frontend')"
+	kindExcludeDesc     = "Comma-separated list of object kinds to drop from every Template (e.g. 'BuildConfig,ImageStream')"
+	umbrellaDesc        = "Emit a single umbrella chart with one subchart per Template under charts/, instead of one chart per Template"
+)
+
+var (
+	tplDir          string
+	namespaceFilter string
+	labelSelector   string
+	kindExclude     string
+	umbrella        bool
+
+	convertAllCmd = &cobra.Command{
+		Use:   "convert-all",
+		Short: "Convert every OpenShift Template in a directory into Helm charts.",
+		Long:  `Walks a directory of OpenShift Templates and converts all of them in one pass, optionally scoping each Template's objects by namespace, label selector and kind, and optionally assembling the result into a single umbrella chart.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConvertAll()
+		},
+	}
+)
+
+func init() {
+	convertAllCmd.Flags().StringVarP(&tplDir, "template-dir", "d", tplDirDefault, tplDirUsage)
+	convertAllCmd.Flags().StringVarP(&chartPath, "chart", "c", chartPathDefault, chartPathUsage)
+	convertAllCmd.Flags().StringVar(&namespaceFilter, "namespace-filter", "", namespaceFilterDesc)
+	convertAllCmd.Flags().StringVar(&labelSelector, "label-selector", "", labelSelectorDesc)
+	convertAllCmd.Flags().StringVar(&kindExclude, "kind-exclude", "", kindExcludeDesc)
+	convertAllCmd.Flags().BoolVar(&umbrella, "umbrella", false, umbrellaDesc)
+	rootCmd.AddCommand(convertAllCmd)
+}
+
+// runConvertAll walks tplDir, converts every Template file it finds
+// (applying the namespace/label/kind filters), and writes the resulting
+// charts under chartPath - either side by side, or as subcharts of a
+// single umbrella chart when umbrella is set.
+func runConvertAll() error {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return fmt.Errorf("::: ERROR - convert-all - invalid --label-selector %q: %v", labelSelector, err)
+	}
+	excludedKinds := map[string]bool{}
+	for _, k := range strings.Split(kindExclude, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			excludedKinds[k] = true
+		}
+	}
+
+	tplFiles, err := findTemplateFiles(tplDir)
+	if err != nil {
+		return err
+	}
+
+	var charts []*chart.Chart
+	for _, tplFile := range tplFiles {
+		ch, err := convertTemplateFile(tplFile, selector, excludedKinds)
+		if err != nil {
+			return err
+		}
+		if ch == nil {
+			log.Printf("::: INFO - convert-all - %s produced no objects after filtering, skipping", tplFile)
+			continue
+		}
+		charts = append(charts, ch)
+	}
+
+	if len(charts) == 0 {
+		log.Printf("::: INFO - convert-all - no Templates found under %s", tplDir)
+		return nil
+	}
+
+	if !umbrella {
+		for _, ch := range charts {
+			if err := chartutil.SaveDir(ch, chartPath); err != nil {
+				return fmt.Errorf("::: ERROR - convert-all - failed to save chart %s: %v", ch.Metadata.Name, err)
+			}
+		}
+		return nil
+	}
+
+	umbrellaChart := buildUmbrellaChart(filepath.Base(filepath.Clean(tplDir)), charts)
+	if err := chartutil.SaveDir(umbrellaChart, chartPath); err != nil {
+		return fmt.Errorf("::: ERROR - convert-all - failed to save umbrella chart %s: %v", umbrellaChart.Metadata.Name, err)
+	}
+	return nil
+}
+
+// findTemplateFiles returns every .yaml/.yml file under dir, sorted by walk order.
+func findTemplateFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("::: ERROR - convert-all - failed to walk %s: %v", dir, err)
+	}
+	return files, nil
+}
+
+// convertTemplateFile parses the Template at path, drops every object that
+// doesn't pass the namespace/label/kind filters, and converts what's left
+// into a chart.Chart. It returns a nil chart (not an error) when every
+// object in the Template is filtered out.
+func convertTemplateFile(path string, selector labels.Selector, excludedKinds map[string]bool) (*chart.Chart, error) {
+	var myTemplate template.Template
+
+	yamlFile, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("::: ERROR - convert-all - couldn't load template %s: %v", path, err)
+	}
+
+	jsonB, err := yaml.YAMLToJSON(yamlFile)
+	if err != nil {
+		return nil, fmt.Errorf("::: ERROR - convert-all - error transforming yaml to json for %s: %v", path, err)
+	}
+	if err := json.Unmarshal(jsonB, &myTemplate); err != nil {
+		return nil, fmt.Errorf("::: ERROR - convert-all - unable to marshal template %s: %v", path, err)
+	}
+
+	filtered, err := filterObjects(myTemplate.Objects, selector, excludedKinds)
+	if err != nil {
+		return nil, fmt.Errorf("::: ERROR - convert-all - failed to filter objects in %s: %v", path, err)
+	}
+	if len(filtered) == 0 {
+		return nil, nil
+	}
+	myTemplate.Objects = filtered
+
+	// Reuse the exact same Template->Chart pipeline 'convert' uses, so
+	// convert-all never drifts from it (NOTES.txt, the connection-test hook,
+	// Chart.yaml icon/keywords all come along for free). The --lint run and
+	// the ingress.yaml wrapping that 'convert' applies after saving are not
+	// part of buildChart, so convert-all does neither.
+	ch, err := buildChart(&myTemplate, path)
+	if err != nil {
+		return nil, fmt.Errorf("::: ERROR - convert-all - failed to build chart for %s: %v", path, err)
+	}
+	return ch, nil
+}
+
+// filterObjects returns the subset of objects whose namespace/labels/kind
+// pass the namespace-filter, label-selector and kind-exclude flags.
+func filterObjects(objects []runtime.RawExtension, selector labels.Selector, excludedKinds map[string]bool) ([]runtime.RawExtension, error) {
+	var kept []runtime.RawExtension
+	for _, o := range objects {
+		var k8sR unstructured.Unstructured
+		if err := json.Unmarshal(o.Raw, &k8sR); err != nil {
+			return nil, err
+		}
+
+		if excludedKinds[k8sR.GetKind()] {
+			continue
+		}
+		if namespaceFilter != "" && k8sR.GetNamespace() != namespaceFilter {
+			continue
+		}
+		if !selector.Empty() && !selector.Matches(labels.Set(k8sR.GetLabels())) {
+			continue
+		}
+
+		kept = append(kept, o)
+	}
+	return kept, nil
+}
+
+// buildUmbrellaChart wraps every converted Template chart as a subchart of
+// a parent chart named name, merging each subchart's values.yaml under its
+// own key and recording a Chart.yaml dependency entry per subchart. Any
+// parameter shared identically across two or more subcharts is deduplicated
+// into a single values.yaml "global" entry instead of being repeated once
+// per subchart - subcharts that disagree on a value keep it as a
+// subchart-local override.
+func buildUmbrellaChart(name string, subcharts []*chart.Chart) *chart.Chart {
+	parent := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:       name,
+			APIVersion: "v2",
+			Version:    "v0.0.1",
+			AppVersion: "v0.0.1",
+		},
+		Values: map[string]interface{}{},
+	}
+
+	if global := dedupeSharedParameters(subcharts); len(global) > 0 {
+		parent.Values["global"] = global
+	}
+
+	for _, sub := range subcharts {
+		parent.Metadata.Dependencies = append(parent.Metadata.Dependencies, &chart.Dependency{
+			Name:       sub.Metadata.Name,
+			Version:    sub.Metadata.Version,
+			Repository: "file://./charts/" + sub.Metadata.Name,
+		})
+		parent.Values[sub.Metadata.Name] = sub.Values
+		parent.AddDependency(sub)
+	}
+
+	valuesAsByte, err := yaml.Marshal(parent.Values)
+	checkErr(err, "::: ERROR - convert-all - failed converting umbrella values to YAML")
+	parent.Raw = []*chart.File{{Name: "values.yaml", Data: valuesAsByte}}
+
+	return parent
+}
+
+// dedupeSharedParameters finds every values.yaml key that two or more
+// subcharts define with an identical value, removes it from each of those
+// subcharts' own Values (and rewrites the subchart's own '.Values.<key>'
+// template references to '.Values.global.<key>', since Helm injects the
+// umbrella's 'global' values into every subchart automatically), and
+// returns the deduplicated key/value pairs to be stored once under the
+// umbrella's own "global" values. A key whose value differs between
+// subcharts is left untouched, so it remains a subchart-local override.
+func dedupeSharedParameters(subcharts []*chart.Chart) map[string]interface{} {
+	type occurrence struct {
+		sub   *chart.Chart
+		value interface{}
+	}
+
+	occurrences := map[string][]occurrence{}
+	for _, sub := range subcharts {
+		for key, value := range sub.Values {
+			occurrences[key] = append(occurrences[key], occurrence{sub: sub, value: value})
+		}
+	}
+
+	global := map[string]interface{}{}
+	for key, occs := range occurrences {
+		if len(occs) < 2 {
+			continue
+		}
+
+		identical := true
+		for _, o := range occs[1:] {
+			if !reflect.DeepEqual(o.value, occs[0].value) {
+				identical = false
+				break
+			}
+		}
+		if !identical {
+			continue
+		}
+
+		global[key] = occs[0].value
+
+		valuesRef := regexp.MustCompile(`\.Values\.` + regexp.QuoteMeta(key) + `\b`)
+		globalRef := ".Values.global." + key
+		for _, o := range occs {
+			delete(o.sub.Values, key)
+			for _, tf := range o.sub.Templates {
+				tf.Data = valuesRef.ReplaceAll(tf.Data, []byte(globalRef))
+			}
+		}
+	}
+
+	return global
+}
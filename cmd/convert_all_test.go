@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func subchart(name string, values map[string]interface{}, templateData string) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: name, APIVersion: "v2", Version: "v0.0.1", AppVersion: "v0.0.1"},
+		Values:   values,
+		Templates: []*chart.File{{
+			Name: "templates/deployment.yaml",
+			Data: []byte(templateData),
+		}},
+	}
+}
+
+func TestBuildUmbrellaChartDeduplicatesIdenticalSharedParameters(t *testing.T) {
+	app1 := subchart("app1", map[string]interface{}{
+		"image_registry": "quay.io/myorg",
+		"replicas":       int64(2),
+	}, "image: {{ .Values.image_registry }}/app1\nreplicas: {{ .Values.replicas }}\n")
+
+	app2 := subchart("app2", map[string]interface{}{
+		"image_registry": "quay.io/myorg",
+		"replicas":       int64(3),
+	}, "image: {{ .Values.image_registry }}/app2\nreplicas: {{ .Values.replicas }}\n")
+
+	umbrella := buildUmbrellaChart("myapps", []*chart.Chart{app1, app2})
+
+	global, ok := umbrella.Values["global"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected umbrella.Values[\"global\"] to be set, got %#v", umbrella.Values["global"])
+	}
+	if got, want := global["image_registry"], "quay.io/myorg"; got != want {
+		t.Errorf("global[image_registry] = %v, want %v", got, want)
+	}
+
+	// replicas differs between app1 (2) and app2 (3), so it must stay a
+	// subchart-local override rather than being hoisted to global.
+	if _, stillShared := global["replicas"]; stillShared {
+		t.Errorf("replicas differs between subcharts and must not be deduplicated, got global[replicas] = %v", global["replicas"])
+	}
+	if app1.Values["replicas"] != int64(2) {
+		t.Errorf("app1's own replicas override was lost: %v", app1.Values["replicas"])
+	}
+	if app2.Values["replicas"] != int64(3) {
+		t.Errorf("app2's own replicas override was lost: %v", app2.Values["replicas"])
+	}
+
+	// The deduplicated key must be gone from each subchart's own Values...
+	if _, stillLocal := app1.Values["image_registry"]; stillLocal {
+		t.Errorf("app1.Values[image_registry] should have been removed in favor of global, got %v", app1.Values["image_registry"])
+	}
+	// ...and every subchart template referencing it must now point at .Values.global.
+	if !strings.Contains(string(app1.Templates[0].Data), "{{ .Values.global.image_registry }}") {
+		t.Errorf("app1 template wasn't rewritten to reference .Values.global.image_registry: %s", app1.Templates[0].Data)
+	}
+	if !strings.Contains(string(app2.Templates[0].Data), "{{ .Values.global.image_registry }}") {
+		t.Errorf("app2 template wasn't rewritten to reference .Values.global.image_registry: %s", app2.Templates[0].Data)
+	}
+	// replicas wasn't deduplicated, so its template reference must be untouched.
+	if !strings.Contains(string(app1.Templates[0].Data), "{{ .Values.replicas }}") {
+		t.Errorf("app1 template's non-deduplicated .Values.replicas reference should be untouched: %s", app1.Templates[0].Data)
+	}
+}
+
+func TestBuildUmbrellaChartSingleSubchartNoDeduplication(t *testing.T) {
+	app1 := subchart("app1", map[string]interface{}{
+		"image_registry": "quay.io/myorg",
+	}, "image: {{ .Values.image_registry }}/app1\n")
+
+	umbrella := buildUmbrellaChart("myapps", []*chart.Chart{app1})
+
+	if _, ok := umbrella.Values["global"]; ok {
+		t.Errorf("a single subchart has nothing to deduplicate against, expected no global key, got %v", umbrella.Values["global"])
+	}
+	if app1.Values["image_registry"] != "quay.io/myorg" {
+		t.Errorf("app1's own value should be untouched when there's nothing to deduplicate: %v", app1.Values["image_registry"])
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	template "github.com/openshift/api/template/v1"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestParamsToValuesTypedIntegerReplicas(t *testing.T) {
+	params := []template.Parameter{{Name: "REPLICAS", Value: "3"}}
+	templates := []*chart.File{{
+		Name: "templates/deployment.yaml",
+		Data: []byte("spec:\n  replicas: ${{REPLICAS}}\n"),
+	}}
+	values := map[string]interface{}{}
+
+	if err := paramsToValues(&params, &values, &templates); err != nil {
+		t.Fatalf("paramsToValues returned an error: %v", err)
+	}
+
+	if got, want := values["replicas"], int64(3); got != want {
+		t.Errorf("values[replicas] = %v (%T), want %v (int64)", got, got, want)
+	}
+	if !strings.Contains(string(templates[0].Data), "{{ .Values.replicas | toJson }}") {
+		t.Errorf("template not substituted correctly: %s", templates[0].Data)
+	}
+}
+
+func TestParamsToValuesTypedBooleanFlag(t *testing.T) {
+	params := []template.Parameter{{Name: "DEBUG", Value: "true"}}
+	templates := []*chart.File{{
+		Name: "templates/deployment.yaml",
+		Data: []byte("env:\n  - name: DEBUG\n    value: ${{DEBUG}}\n"),
+	}}
+	values := map[string]interface{}{}
+
+	if err := paramsToValues(&params, &values, &templates); err != nil {
+		t.Fatalf("paramsToValues returned an error: %v", err)
+	}
+
+	if got, want := values["debug"], true; got != want {
+		t.Errorf("values[debug] = %v (%T), want %v (bool)", got, got, want)
+	}
+}
+
+func TestParamsToValuesMultiLinePEMParameter(t *testing.T) {
+	// Deliberately not templates/secret.yaml: that destination always routes
+	// through b64enc regardless of newlines (Secret.data is base64, not
+	// literal YAML), so the toYaml|nindent branch can only be exercised by
+	// a ConfigMap-shaped destination.
+	pem := "-----BEGIN CERTIFICATE-----\nMIIB...\n-----END CERTIFICATE-----"
+	params := []template.Parameter{{Name: "TLS_CERT", Value: pem}}
+	templates := []*chart.File{{
+		Name: "templates/configmap.yaml",
+		Data: []byte("data:\n  tls.crt: ${{TLS_CERT}}\n"),
+	}}
+	values := map[string]interface{}{}
+
+	if err := paramsToValues(&params, &values, &templates); err != nil {
+		t.Fatalf("paramsToValues returned an error: %v", err)
+	}
+
+	if !strings.Contains(string(templates[0].Data), "toYaml | nindent 4") {
+		t.Errorf("multi-line value should be rendered via toYaml|nindent, got: %s", templates[0].Data)
+	}
+}
+
+func TestParamsToValuesGeneratedPasswordIsNotBakedIntoValues(t *testing.T) {
+	params := []template.Parameter{{
+		Name:     "DB_PASSWORD",
+		Generate: "expression",
+		From:     "[a-zA-Z0-9]{16}",
+	}}
+	templates := []*chart.File{{
+		Name: "templates/secret.yaml",
+		Data: []byte("data:\n  password: ${DB_PASSWORD}\n"),
+	}}
+	values := map[string]interface{}{}
+
+	if err := paramsToValues(&params, &values, &templates); err != nil {
+		t.Fatalf("paramsToValues returned an error: %v", err)
+	}
+
+	if _, set := values["db_password"]; set {
+		t.Errorf("a generated parameter must not get a values.yaml default (Helm never templates values.yaml), got %v", values["db_password"])
+	}
+	if !strings.Contains(string(templates[0].Data), "default (randAlphaNum 16)") {
+		t.Errorf("generated default should be emitted as an in-template 'default (...)' expression, got: %s", templates[0].Data)
+	}
+}
+
+func TestParamsToValuesSecretParamDefaultIsDecoded(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("secretpass"))
+	params := []template.Parameter{{Name: "DB_PASSWORD", Value: encoded}}
+	templates := []*chart.File{{
+		Name: "templates/secret.yaml",
+		Data: []byte("data:\n  password: ${{DB_PASSWORD}}\n"),
+	}}
+	values := map[string]interface{}{}
+
+	if err := paramsToValues(&params, &values, &templates); err != nil {
+		t.Fatalf("paramsToValues returned an error: %v", err)
+	}
+
+	if got, want := values["db_password"], "secretpass"; got != want {
+		t.Errorf("values[db_password] = %q, want decoded value %q (the template already applies b64enc)", got, want)
+	}
+	if !strings.Contains(string(templates[0].Data), "| b64enc") {
+		t.Errorf("secret template should still route the value through b64enc, got: %s", templates[0].Data)
+	}
+}
@@ -0,0 +1,450 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	lintChartPathDefault = "."
+	lintChartPathUsage   = "Path to the Helm chart to lint"
+	lintFailOnDefault    = "error"
+	lintFailOnUsage      = "Minimum severity that causes 'lint'/'convert --lint' to exit non-zero: warning|error"
+)
+
+var (
+	lintChartPath string
+	lintFailOn    string
+	convertLint   bool
+
+	lintCmd = &cobra.Command{
+		Use:   "lint",
+		Short: "Run structural best-practice checks against a generated Helm chart.",
+		Long:  `Renders the chart with a stub values file and evaluates it against a fixed set of kube-linter-style checks.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diags, err := lintChart(lintChartPath)
+			if err != nil {
+				return err
+			}
+			return reportDiagnostics(diags, lintFailOn)
+		},
+	}
+)
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintChartPath, "chart", "c", lintChartPathDefault, lintChartPathUsage)
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", lintFailOnDefault, lintFailOnUsage)
+	rootCmd.AddCommand(lintCmd)
+
+	convertCmd.Flags().BoolVar(&convertLint, "lint", false, "Run the built-in chart linter against the chart just generated")
+	convertCmd.Flags().StringVar(&lintFailOn, "fail-on", lintFailOnDefault, lintFailOnUsage)
+}
+
+// Severity of a lint Diagnostic.
+const (
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Diagnostic is a single finding reported by a Check.
+type Diagnostic struct {
+	Check       string
+	Severity    string
+	Message     string
+	Remediation string
+}
+
+// Check is a single structural rule evaluated against every object rendered
+// from the chart's templates.
+type Check interface {
+	Name() string
+	Severity() string
+	Run(objs []unstructured.Unstructured) []Diagnostic
+}
+
+// builtinChecks are the checks run by 'lint' and 'convert --lint'.
+var builtinChecks = []Check{
+	danglingServiceCheck{},
+	defaultServiceAccountCheck{},
+	noResourceLimitsCheck{},
+	runAsNonRootCheck{},
+	ingressTargetMissingCheck{},
+	latestTagCheck{},
+}
+
+// lintChart loads the chart at path, renders it with a stub values file,
+// parses the rendered manifests back into unstructured.Unstructured objects
+// and runs every builtin Check against them.
+func lintChart(path string) ([]Diagnostic, error) {
+	loadedChart, err := loader.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("::: ERROR - lint - failed to load chart %s: %v", path, err)
+	}
+
+	objs, err := renderToObjects(loadedChart)
+	if err != nil {
+		return nil, err
+	}
+
+	return runChecks(objs), nil
+}
+
+// renderToObjects renders ch with a stub values file (the chart's own
+// defaults, so required values aren't left empty) and parses every
+// document of every rendered template back into an unstructured.Unstructured.
+func renderToObjects(ch *chart.Chart) ([]unstructured.Unstructured, error) {
+	renderValues, err := chartutil.ToRenderValues(ch, ch.Values, chartutil.ReleaseOptions{
+		Name:      "release-name",
+		Namespace: "default",
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("::: ERROR - lint - failed to build stub render values: %v", err)
+	}
+
+	rendered, err := engine.Render(ch, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("::: ERROR - lint - failed to render chart templates: %v", err)
+	}
+
+	var objs []unstructured.Unstructured
+	for name, content := range rendered {
+		if strings.HasSuffix(name, "NOTES.txt") || strings.TrimSpace(content) == "" {
+			continue
+		}
+		for _, doc := range bytes.Split([]byte(content), []byte("\n---\n")) {
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+			var obj unstructured.Unstructured
+			if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+				log.Printf("::: WARNING - lint - failed to parse rendered template %s: %v", name, err)
+				continue
+			}
+			if obj.GetKind() == "" {
+				continue
+			}
+			objs = append(objs, obj)
+		}
+	}
+
+	return objs, nil
+}
+
+// runChecks evaluates every builtin Check against objs and returns the
+// aggregated diagnostics.
+func runChecks(objs []unstructured.Unstructured) []Diagnostic {
+	var diags []Diagnostic
+	for _, c := range builtinChecks {
+		diags = append(diags, c.Run(objs)...)
+	}
+	return diags
+}
+
+// reportDiagnostics prints diags and returns a non-nil error if any of them
+// is at least as severe as failOn.
+func reportDiagnostics(diags []Diagnostic, failOn string) error {
+	if len(diags) == 0 {
+		log.Printf("::: INFO - lint - no issues found")
+		return nil
+	}
+
+	fail := false
+	for _, d := range diags {
+		log.Printf("::: %s - [%s] %s (%s)", strings.ToUpper(d.Severity), d.Check, d.Message, d.Remediation)
+		if severityAtLeast(d.Severity, failOn) {
+			fail = true
+		}
+	}
+
+	if fail {
+		return fmt.Errorf("::: ERROR - lint - found issues at or above severity %q", failOn)
+	}
+	return nil
+}
+
+func severityAtLeast(severity, threshold string) bool {
+	rank := map[string]int{SeverityWarning: 1, SeverityError: 2}
+	return rank[severity] >= rank[threshold]
+}
+
+// podSpecs extracts every pod template spec (map[string]interface{}) found
+// in objs, covering Deployment/DeploymentConfig-shaped workloads.
+func podSpecs(objs []unstructured.Unstructured) []map[string]interface{} {
+	var specs []map[string]interface{}
+	for _, o := range objs {
+		switch o.GetKind() {
+		case "Deployment", "DeploymentConfig", "StatefulSet", "DaemonSet":
+			spec, found, _ := unstructured.NestedMap(o.Object, "spec", "template", "spec")
+			if found {
+				specs = append(specs, spec)
+			}
+		}
+	}
+	return specs
+}
+
+// podTemplateLabelSets extracts the pod template labels
+// (spec.template.metadata.labels) of every Deployment/DeploymentConfig/
+// StatefulSet/DaemonSet in objs. This is distinct from podSpecs, which
+// returns spec.template.spec (the PodSpec) - the PodSpec itself has no
+// 'metadata', so labels have to be read from the workload object directly.
+func podTemplateLabelSets(objs []unstructured.Unstructured) []map[string]string {
+	var sets []map[string]string
+	for _, o := range objs {
+		switch o.GetKind() {
+		case "Deployment", "DeploymentConfig", "StatefulSet", "DaemonSet":
+			labels, found, _ := unstructured.NestedStringMap(o.Object, "spec", "template", "metadata", "labels")
+			if found {
+				sets = append(sets, labels)
+			}
+		}
+	}
+	return sets
+}
+
+func containers(podSpec map[string]interface{}) []map[string]interface{} {
+	var out []map[string]interface{}
+	raw, found, _ := unstructured.NestedSlice(podSpec, "containers")
+	if !found {
+		return out
+	}
+	for _, c := range raw {
+		if m, ok := c.(map[string]interface{}); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// danglingServiceCheck flags a Service whose selector matches no
+// Deployment/DeploymentConfig pod template labels in the same chart.
+type danglingServiceCheck struct{}
+
+func (danglingServiceCheck) Name() string     { return "dangling-service" }
+func (danglingServiceCheck) Severity() string { return SeverityWarning }
+
+func (c danglingServiceCheck) Run(objs []unstructured.Unstructured) []Diagnostic {
+	podLabelSets := podTemplateLabelSets(objs)
+
+	var diags []Diagnostic
+	for _, o := range objs {
+		if o.GetKind() != "Service" {
+			continue
+		}
+		selector, found, _ := unstructured.NestedStringMap(o.Object, "spec", "selector")
+		if !found || len(selector) == 0 {
+			continue
+		}
+
+		matched := false
+		for _, labels := range podLabelSets {
+			if selectorMatches(selector, labels) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			diags = append(diags, Diagnostic{
+				Check:       c.Name(),
+				Severity:    c.Severity(),
+				Message:     fmt.Sprintf("Service %q selector matches no workload in this chart", o.GetName()),
+				Remediation: "Make sure the Service selector matches the labels of a Deployment/DeploymentConfig pod template",
+			})
+		}
+	}
+	return diags
+}
+
+func selectorMatches(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultServiceAccountCheck flags a pod spec that runs under the empty or
+// "default" ServiceAccount.
+type defaultServiceAccountCheck struct{}
+
+func (defaultServiceAccountCheck) Name() string     { return "default-service-account" }
+func (defaultServiceAccountCheck) Severity() string { return SeverityWarning }
+
+func (c defaultServiceAccountCheck) Run(objs []unstructured.Unstructured) []Diagnostic {
+	var diags []Diagnostic
+	for i, spec := range podSpecs(objs) {
+		sa, _, _ := unstructured.NestedString(spec, "serviceAccountName")
+		if sa == "" || sa == "default" {
+			diags = append(diags, Diagnostic{
+				Check:       c.Name(),
+				Severity:    c.Severity(),
+				Message:     fmt.Sprintf("workload #%d uses the default ServiceAccount", i),
+				Remediation: "Set spec.template.spec.serviceAccountName to a dedicated ServiceAccount",
+			})
+		}
+	}
+	return diags
+}
+
+// noResourceLimitsCheck flags a container that is missing either
+// resources.requests or resources.limits.
+type noResourceLimitsCheck struct{}
+
+func (noResourceLimitsCheck) Name() string     { return "no-resource-limits" }
+func (noResourceLimitsCheck) Severity() string { return SeverityError }
+
+func (c noResourceLimitsCheck) Run(objs []unstructured.Unstructured) []Diagnostic {
+	var diags []Diagnostic
+	for _, spec := range podSpecs(objs) {
+		for _, cnt := range containers(spec) {
+			name, _, _ := unstructured.NestedString(cnt, "name")
+			_, hasRequests, _ := unstructured.NestedMap(cnt, "resources", "requests")
+			_, hasLimits, _ := unstructured.NestedMap(cnt, "resources", "limits")
+			if !hasRequests || !hasLimits {
+				diags = append(diags, Diagnostic{
+					Check:       c.Name(),
+					Severity:    c.Severity(),
+					Message:     fmt.Sprintf("container %q is missing resources.requests and/or resources.limits", name),
+					Remediation: "Set both resources.requests and resources.limits on every container",
+				})
+			}
+		}
+	}
+	return diags
+}
+
+// runAsNonRootCheck flags a container/pod that doesn't explicitly refuse to
+// run as root.
+type runAsNonRootCheck struct{}
+
+func (runAsNonRootCheck) Name() string     { return "run-as-non-root" }
+func (runAsNonRootCheck) Severity() string { return SeverityWarning }
+
+func (c runAsNonRootCheck) Run(objs []unstructured.Unstructured) []Diagnostic {
+	var diags []Diagnostic
+	for i, spec := range podSpecs(objs) {
+		podRunAsNonRoot, podSet, _ := unstructured.NestedBool(spec, "securityContext", "runAsNonRoot")
+		if podSet && podRunAsNonRoot {
+			continue
+		}
+		for _, cnt := range containers(spec) {
+			name, _, _ := unstructured.NestedString(cnt, "name")
+			runAsNonRoot, set, _ := unstructured.NestedBool(cnt, "securityContext", "runAsNonRoot")
+			if !set || !runAsNonRoot {
+				diags = append(diags, Diagnostic{
+					Check:       c.Name(),
+					Severity:    c.Severity(),
+					Message:     fmt.Sprintf("container %q (workload #%d) doesn't set securityContext.runAsNonRoot: true", name, i),
+					Remediation: "Set securityContext.runAsNonRoot: true on the pod or every container",
+				})
+			}
+		}
+	}
+	return diags
+}
+
+// ingressTargetMissingCheck flags an Ingress backend that names a
+// Service/port not present in the chart.
+type ingressTargetMissingCheck struct{}
+
+func (ingressTargetMissingCheck) Name() string     { return "ingress-target-missing" }
+func (ingressTargetMissingCheck) Severity() string { return SeverityError }
+
+func (c ingressTargetMissingCheck) Run(objs []unstructured.Unstructured) []Diagnostic {
+	servicePorts := map[string]map[int64]bool{}
+	for _, o := range objs {
+		if o.GetKind() != "Service" {
+			continue
+		}
+		ports, _, _ := unstructured.NestedSlice(o.Object, "spec", "ports")
+		m := map[int64]bool{}
+		for _, p := range ports {
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// Rendered manifests decode numbers as float64, which
+			// NestedInt64 rejects, so normalize through toInt64.
+			if n := toInt64(pm["port"]); n != 0 {
+				m[n] = true
+			}
+		}
+		servicePorts[o.GetName()] = m
+	}
+
+	var diags []Diagnostic
+	for _, o := range objs {
+		if o.GetKind() != "Ingress" {
+			continue
+		}
+		rules, _, _ := unstructured.NestedSlice(o.Object, "spec", "rules")
+		for _, r := range rules {
+			rm, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			paths, _, _ := unstructured.NestedSlice(rm, "http", "paths")
+			for _, p := range paths {
+				pm, ok := p.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				svcName, _, _ := unstructured.NestedString(pm, "backend", "service", "name")
+				rawPort, _, _ := unstructured.NestedFieldNoCopy(pm, "backend", "service", "port", "number")
+				svcPort := toInt64(rawPort)
+
+				ports, known := servicePorts[svcName]
+				if !known || !ports[svcPort] {
+					diags = append(diags, Diagnostic{
+						Check:       c.Name(),
+						Severity:    c.Severity(),
+						Message:     fmt.Sprintf("Ingress %q targets Service %q port %d, which doesn't exist in this chart", o.GetName(), svcName, svcPort),
+						Remediation: "Point the Ingress backend at a Service/port defined in this chart",
+					})
+				}
+			}
+		}
+	}
+	return diags
+}
+
+// latestTagCheck flags a container image with no tag or the "latest" tag.
+type latestTagCheck struct{}
+
+func (latestTagCheck) Name() string     { return "latest-tag" }
+func (latestTagCheck) Severity() string { return SeverityWarning }
+
+func (c latestTagCheck) Run(objs []unstructured.Unstructured) []Diagnostic {
+	var diags []Diagnostic
+	for _, spec := range podSpecs(objs) {
+		for _, cnt := range containers(spec) {
+			name, _, _ := unstructured.NestedString(cnt, "name")
+			image, _, _ := unstructured.NestedString(cnt, "image")
+			parts := strings.Split(image, ":")
+			tag := ""
+			if len(parts) > 1 {
+				tag = parts[len(parts)-1]
+			}
+			if tag == "" || tag == "latest" {
+				diags = append(diags, Diagnostic{
+					Check:       c.Name(),
+					Severity:    c.Severity(),
+					Message:     fmt.Sprintf("container %q uses image %q with no pinned tag", name, image),
+					Remediation: "Pin the image to a specific, immutable tag instead of 'latest'",
+				})
+			}
+		}
+	}
+	return diags
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func deployment(name string, labels map[string]string, containers []interface{}) unstructured.Unstructured {
+	labelsIface := map[string]interface{}{}
+	for k, v := range labels {
+		labelsIface[k] = v
+	}
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": labelsIface},
+				"spec": map[string]interface{}{
+					"containers": containers,
+				},
+			},
+		},
+	}}
+}
+
+func service(name string, selector map[string]string, ports []interface{}) unstructured.Unstructured {
+	selectorIface := map[string]interface{}{}
+	for k, v := range selector {
+		selectorIface[k] = v
+	}
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"selector": selectorIface,
+			"ports":    ports,
+		},
+	}}
+}
+
+func TestDanglingServiceCheckMatchingWorkloadNoDiagnostic(t *testing.T) {
+	objs := []unstructured.Unstructured{
+		deployment("myapp", map[string]string{"app": "myapp"}, nil),
+		service("myapp", map[string]string{"app": "myapp"}, []interface{}{
+			map[string]interface{}{"port": int64(80)},
+		}),
+	}
+
+	diags := danglingServiceCheck{}.Run(objs)
+	if len(diags) != 0 {
+		t.Errorf("expected no diagnostics for a Service whose selector matches its Deployment's pod template labels, got %+v", diags)
+	}
+}
+
+func TestDanglingServiceCheckNoMatchingWorkload(t *testing.T) {
+	objs := []unstructured.Unstructured{
+		deployment("myapp", map[string]string{"app": "other"}, nil),
+		service("myapp", map[string]string{"app": "myapp"}, []interface{}{
+			map[string]interface{}{"port": int64(80)},
+		}),
+	}
+
+	diags := danglingServiceCheck{}.Run(objs)
+	if len(diags) != 1 {
+		t.Fatalf("expected exactly one diagnostic for a dangling Service, got %+v", diags)
+	}
+	if diags[0].Check != "dangling-service" {
+		t.Errorf("Check = %q, want dangling-service", diags[0].Check)
+	}
+}
+
+func TestIngressTargetMissingCheckKnownPort(t *testing.T) {
+	objs := []unstructured.Unstructured{
+		service("myapp", map[string]string{"app": "myapp"}, []interface{}{
+			map[string]interface{}{"port": int64(80)},
+		}),
+		{Object: map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"metadata":   map[string]interface{}{"name": "ingress-myapp"},
+			"spec": map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"http": map[string]interface{}{
+							"paths": []interface{}{
+								map[string]interface{}{
+									"backend": map[string]interface{}{
+										"service": map[string]interface{}{
+											"name": "myapp",
+											"port": map[string]interface{}{"number": int64(80)},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}},
+	}
+
+	diags := ingressTargetMissingCheck{}.Run(objs)
+	if len(diags) != 0 {
+		t.Errorf("expected no diagnostics when the Ingress targets a port the Service declares, got %+v", diags)
+	}
+}
+
+func TestIngressTargetMissingCheckUnknownPort(t *testing.T) {
+	objs := []unstructured.Unstructured{
+		service("myapp", map[string]string{"app": "myapp"}, []interface{}{
+			map[string]interface{}{"port": int64(80)},
+		}),
+		{Object: map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"metadata":   map[string]interface{}{"name": "ingress-myapp"},
+			"spec": map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"http": map[string]interface{}{
+							"paths": []interface{}{
+								map[string]interface{}{
+									"backend": map[string]interface{}{
+										"service": map[string]interface{}{
+											"name": "myapp",
+											"port": map[string]interface{}{"number": int64(8080)},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}},
+	}
+
+	diags := ingressTargetMissingCheck{}.Run(objs)
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic when the Ingress targets a port the Service doesn't declare, got %+v", diags)
+	}
+	if diags[0].Check != "ingress-target-missing" {
+		t.Errorf("Check = %q, want ingress-target-missing", diags[0].Check)
+	}
+}
+
+func TestNoResourceLimitsCheck(t *testing.T) {
+	withLimits := map[string]interface{}{
+		"name": "withlimits",
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": "100m"},
+			"limits":   map[string]interface{}{"cpu": "200m"},
+		},
+	}
+	withoutLimits := map[string]interface{}{
+		"name": "nolimits",
+	}
+
+	objs := []unstructured.Unstructured{
+		deployment("myapp", map[string]string{"app": "myapp"}, []interface{}{withLimits, withoutLimits}),
+	}
+
+	diags := noResourceLimitsCheck{}.Run(objs)
+	if len(diags) != 1 {
+		t.Fatalf("expected exactly one diagnostic (for the container missing resources), got %+v", diags)
+	}
+	if diags[0].Check != "no-resource-limits" {
+		t.Errorf("Check = %q, want no-resource-limits", diags[0].Check)
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	template "github.com/openshift/api/template/v1"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// knownCatalogIcons maps an OpenShift Template "iconClass" annotation to a
+// Chart.yaml-compatible icon URL, for the handful of icon classes that ship
+// with the standard OpenShift catalog.
+var knownCatalogIcons = map[string]string{
+	"icon-jboss":          "https://raw.githubusercontent.com/openshift/library/master/icons/jboss.svg",
+	"icon-nodejs":         "https://raw.githubusercontent.com/openshift/library/master/icons/nodejs.svg",
+	"icon-postgresql":     "https://raw.githubusercontent.com/openshift/library/master/icons/postgresql.svg",
+	"icon-mysql-database": "https://raw.githubusercontent.com/openshift/library/master/icons/mysql-database.svg",
+	"icon-redis":          "https://raw.githubusercontent.com/openshift/library/master/icons/redis.svg",
+}
+
+// addAnnotationArtifacts turns the metadata a Template carries beyond
+// 'description'/'appversion' into real chart artifacts: a NOTES.txt built
+// from the Template's message/expose-* annotations, a Helm test hook that
+// exercises the chart's first exposed Service/Ingress, and Chart.yaml
+// icon/keywords derived from iconClass/tags.
+//
+// objs are the objects objectToTemplate produced, i.e. already transformed
+// but *before* paramsToValues rewrites them into Go template syntax - using
+// myChart.Templates here instead would hand invalid YAML to any parser.
+func addAnnotationArtifacts(myTemplate *template.Template, myChart *chart.Chart, objs []unstructured.Unstructured) error {
+	substitute := func(s string) string {
+		for _, pm := range myTemplate.Parameters {
+			s = strings.ReplaceAll(s, fmt.Sprintf("${%s}", pm.Name), fmt.Sprintf("{{ .Values.%s }}", strings.ToLower(pm.Name)))
+		}
+		return s
+	}
+
+	if notes := buildNotes(myTemplate, substitute); notes != "" {
+		myChart.Templates = append(myChart.Templates, &chart.File{
+			Name: "templates/NOTES.txt",
+			Data: []byte(notes),
+		})
+	}
+
+	if test := buildConnectionTest(myChart, objs); test != "" {
+		myChart.Templates = append(myChart.Templates, &chart.File{
+			Name: "templates/tests/connection-test.yaml",
+			Data: []byte(test),
+		})
+	}
+
+	if iconClass := myTemplate.ObjectMeta.Annotations["iconClass"]; iconClass != "" {
+		if icon, ok := knownCatalogIcons[iconClass]; ok {
+			myChart.Metadata.Icon = icon
+		}
+	}
+
+	if tags := myTemplate.ObjectMeta.Annotations["tags"]; tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				myChart.Metadata.Keywords = append(myChart.Metadata.Keywords, tag)
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildNotes renders templates/NOTES.txt from the Template's 'message'
+// field plus any 'template.openshift.io/expose-*' annotation, each run
+// through substitute to turn ${PARAM} into {{ .Values.param }}.
+func buildNotes(myTemplate *template.Template, substitute func(string) string) string {
+	var b strings.Builder
+
+	if myTemplate.Message != "" {
+		b.WriteString(substitute(myTemplate.Message))
+		b.WriteString("\n")
+	}
+
+	// Annotations aren't ordered, so sort the expose-* keys for stable output.
+	var exposeKeys []string
+	for k := range myTemplate.ObjectMeta.Annotations {
+		if strings.HasPrefix(k, "template.openshift.io/expose-") {
+			exposeKeys = append(exposeKeys, k)
+		}
+	}
+	sort.Strings(exposeKeys)
+
+	for _, k := range exposeKeys {
+		label := strings.TrimPrefix(k, "template.openshift.io/expose-")
+		b.WriteString(fmt.Sprintf("%s: %s\n", label, substitute(myTemplate.ObjectMeta.Annotations[k])))
+	}
+
+	return b.String()
+}
+
+// buildConnectionTest emits a Helm test hook pod that wgets the chart's
+// first Service port (or, failing that, the first Ingress host) so that
+// 'helm test' has something meaningful to run against a converted chart.
+// It returns "" when objs exposes nothing to connect to.
+func buildConnectionTest(myChart *chart.Chart, objs []unstructured.Unstructured) string {
+	target := firstConnectionTarget(objs)
+	if target == "" {
+		return ""
+	}
+
+	// This chart ships no _helpers.tpl (it isn't a 'helm create' scaffold),
+	// so there is no "<chart>.fullname" named template to include - prefix
+	// the release name directly instead.
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-` + myChart.Metadata.Name + `-connection-test
+  annotations:
+    "helm.sh/hook": test
+spec:
+  restartPolicy: Never
+  containers:
+    - name: connection-test
+      image: busybox
+      command: ['wget']
+      args: ['` + target + `']
+`
+	return manifest
+}
+
+// firstConnectionTarget returns a wget-able address for the first Service
+// port declared in objs, or, when no Service declares a port, its first
+// Ingress host.
+func firstConnectionTarget(objs []unstructured.Unstructured) string {
+	for _, o := range objs {
+		if o.GetKind() != "Service" {
+			continue
+		}
+		ports, found, _ := unstructured.NestedSlice(o.Object, "spec", "ports")
+		if !found || len(ports) == 0 {
+			continue
+		}
+		pm, ok := ports[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if port := toInt64(pm["port"]); port != 0 {
+			return fmt.Sprintf("http://%s:%d", o.GetName(), port)
+		}
+	}
+
+	for _, o := range objs {
+		if o.GetKind() != "Ingress" {
+			continue
+		}
+		rules, found, _ := unstructured.NestedSlice(o.Object, "spec", "rules")
+		if !found || len(rules) == 0 {
+			continue
+		}
+		rm, ok := rules[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if host, _ := rm["host"].(string); host != "" {
+			return fmt.Sprintf("http://%s", host)
+		}
+	}
+
+	return ""
+}
+
+// toInt64 normalizes a decoded JSON/YAML number into an int64: both
+// encoding/json and sigs.k8s.io/yaml decode numeric literals into
+// interface{} as float64, never int64.
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ConvertContext carries the state that is shared across Transformers while
+// a Template's objects are being converted into chart templates. It replaces
+// the local variables that objectToTemplate used to thread through its
+// (now removed) switch statement.
+type ConvertContext struct {
+	// TemplateLabels are merged into every converted object's labels.
+	TemplateLabels map[string]string
+	// ServicePorts collects the ports declared by Service objects, keyed
+	// by an incrementing index, so that a later Route can resolve its
+	// targetPort name back to a numeric port.
+	ServicePorts map[int]map[string]string
+}
+
+// NewConvertContext returns a ConvertContext ready to be passed to a Registry.
+func NewConvertContext(templateLabels map[string]string) *ConvertContext {
+	return &ConvertContext{
+		TemplateLabels: templateLabels,
+		ServicePorts:   map[int]map[string]string{},
+	}
+}
+
+// Transformer mutates a single unstructured object as part of the
+// Template -> Helm chart conversion. Implementations may rewrite the
+// object's kind/apiVersion/spec in place, or leave it untouched.
+type Transformer interface {
+	Transform(obj *unstructured.Unstructured, ctx *ConvertContext) error
+}
+
+// TransformerFunc adapts a plain function to the Transformer interface.
+type TransformerFunc func(obj *unstructured.Unstructured, ctx *ConvertContext) error
+
+// Transform calls f.
+func (f TransformerFunc) Transform(obj *unstructured.Unstructured, ctx *ConvertContext) error {
+	return f(obj, ctx)
+}
+
+// Registry maps an object kind to the Transformers that run against it.
+type Registry struct {
+	transformers map[string][]Transformer
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{transformers: map[string][]Transformer{}}
+}
+
+// Register adds t to the list of Transformers that run for kind, in
+// addition to whatever is already registered for it.
+func (r *Registry) Register(kind string, t Transformer) {
+	r.transformers[kind] = append(r.transformers[kind], t)
+}
+
+// Transform runs every Transformer registered for obj's kind, in
+// registration order. Objects whose kind has no registered Transformer are
+// passed through unchanged.
+func (r *Registry) Transform(obj *unstructured.Unstructured, ctx *ConvertContext) error {
+	for _, t := range r.transformers[obj.GetKind()] {
+		if err := t.Transform(obj, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultRegistry is the Registry used by objectToTemplate. Downstream code
+// can extend it via RegisterTransformer to support additional
+// OpenShift-specific kinds without forking this package.
+var defaultRegistry = NewRegistry()
+
+// RegisterTransformer adds t as a Transformer for kind on the default
+// Registry used by the 'convert' command.
+func RegisterTransformer(kind string, t Transformer) {
+	defaultRegistry.Register(kind, t)
+}
+
+func init() {
+	RegisterTransformer("DeploymentConfig", TransformerFunc(transformDeploymentConfig))
+	RegisterTransformer("Service", TransformerFunc(transformService))
+	RegisterTransformer("Route", TransformerFunc(transformRoute))
+	RegisterTransformer("BuildConfig", TransformerFunc(transformBuildConfig))
+	RegisterTransformer("ImageStream", TransformerFunc(transformImageStream))
+	RegisterTransformer("RoleBinding", TransformerFunc(transformRoleBinding))
+}
+
+// transformDeploymentConfig converts a DeploymentConfig into a Deployment,
+// dropping the fields that have no Deployment equivalent and normalizing
+// the selector shape. This is the same conversion objectToTemplate used to
+// perform inline for the "DeploymentConfig" case of its switch statement.
+func transformDeploymentConfig(k8sR *unstructured.Unstructured, ctx *ConvertContext) error {
+	log.Printf("::: INFO - Deployment - converting the object from: %s into 'Deployment'", k8sR.GetKind())
+
+	log.Printf("::: INFO - Deployment - change the current apiVersion: %s ", k8sR.GetAPIVersion())
+	k8sR.SetAPIVersion("apps/v1")
+
+	log.Printf("::: INFO - Deployment - change the current object type: %s ", k8sR.GetKind())
+	k8sR.SetKind("Deployment")
+
+	// ::: Delete the following entries:
+	//
+	// 		strategy:
+	// 			activeDeadlineSeconds: 1800
+	// 			type: "rolling"
+	//		selector:
+	//		test:
+	//		triggers:
+	//
+	// 	and might set the full path specifying all the fields: "spec","strategy" and so on
+	log.Printf("::: INFO - Deployment - remove the 'strategy' branch from the object: %s ", k8sR.GetKind())
+	myInterface, _, err := unstructured.NestedFieldNoCopy(k8sR.Object, "spec")
+	if err != nil {
+		return fmt.Errorf(fmt.Sprintf("\n::: ERROR - Deployment - failed to parse the object %s with the following Error: ", k8sR.GetKind()) + err.Error())
+	}
+	unstructured.RemoveNestedField(myInterface.(map[string]interface{}), "strategy")
+	unstructured.RemoveNestedField(myInterface.(map[string]interface{}), "test")
+	unstructured.RemoveNestedField(myInterface.(map[string]interface{}), "triggers")
+
+	//
+	// Get the original selector items tree
+	//
+	existingSelectorMatchLabels, isSelectorExist, err := unstructured.NestedMap(myInterface.(map[string]interface{}), "selector", "matchLabels")
+	if err != nil {
+		return fmt.Errorf("::: ERROR - Deployment - failed to get the 'selector.matchLabels' from DeploymentConfig object: %v", err)
+	} else if isSelectorExist {
+		log.Printf("::: INFO - Deployment - skipping the Selector because is appears as already configured = %s", existingSelectorMatchLabels)
+		return nil
+	}
+
+	existingSelectorInterface, isSelectorToUpdate, err := unstructured.NestedMap(myInterface.(map[string]interface{}), "selector")
+	if err != nil {
+		return fmt.Errorf("::: ERROR - Deployment - failed to get the 'selector' from DeploymentConfig object: %v", err)
+	} else if isSelectorToUpdate {
+		log.Printf("::: INFO - Deployment - selector was found and its value is = %s", existingSelectorInterface)
+
+		unstructured.RemoveNestedField(myInterface.(map[string]interface{}), "selector")
+		unstructured.SetNestedMap(myInterface.(map[string]interface{}), existingSelectorInterface, "selector", "matchLabels")
+	}
+
+	return nil
+}
+
+// transformService collects every port declared by a Service object into
+// ctx.ServicePorts so a later Route->Ingress conversion can resolve a
+// targetPort name back to its numeric port. The Service object itself is
+// left untouched.
+func transformService(k8sR *unstructured.Unstructured, ctx *ConvertContext) error {
+	getServicePorts, _, err := unstructured.NestedFieldNoCopy(k8sR.Object, "spec", "ports")
+	if err != nil {
+		return fmt.Errorf("::: ERROR - Service - failed to get the 'ports' name from the 'service' object: %v", err)
+	}
+
+	ports, ok := getServicePorts.([]interface{})
+	if !ok {
+		return fmt.Errorf("::: ERROR - Service - 'spec.ports' has an unexpected shape: %T", getServicePorts)
+	}
+
+	base := len(ctx.ServicePorts)
+	for key, value := range ports {
+		keyy := key + base
+		ctx.ServicePorts[keyy] = map[string]string{}
+		for kk, vv := range value.(map[string]interface{}) {
+			ctx.ServicePorts[keyy][kk] = fmt.Sprint(vv)
+		}
+	}
+
+	return nil
+}
+
+// transformRoute converts an OpenShift Route into a Kubernetes Ingress,
+// resolving the Route's targetPort against the ports collected by
+// transformService.
+func transformRoute(k8sR *unstructured.Unstructured, ctx *ConvertContext) error {
+	log.Printf("::: INFO - Route - converting the object from: %s into 'Ingress'", k8sR.GetKind())
+
+	getTargetService, _, err := unstructured.NestedFieldNoCopy(k8sR.Object, "spec", "to")
+	if err != nil {
+		return fmt.Errorf("::: ERROR - Route - failed to get the 'service' name from the 'route' object: %v", err)
+	}
+
+	var mTargetService = map[string]string{}
+	for k, v := range getTargetService.(map[string]interface{}) {
+		mTargetService[k] = fmt.Sprint(v)
+		if _, ok := mTargetService["name"]; ok {
+			log.Printf("::: INFO - Route - get the target service name = '%+v' \n", mTargetService["name"])
+		}
+	}
+
+	getTargetPort, _, err := unstructured.NestedFieldNoCopy(k8sR.Object, "spec", "port", "targetPort")
+	if err != nil {
+		return fmt.Errorf("::: ERROR - Route - failed to get the 'target port' from the 'route' object: %v", err)
+	}
+
+	// The Ingress backend must reference the Service's own (client-facing)
+	// 'port', not its 'targetPort' (the container port) - ingressTargetMissingCheck
+	// validates the Ingress against spec.ports[].port, and a plain Service
+	// object is addressed by 'port' from anywhere else in the cluster too.
+	var TargetPort string
+	for _, srvObjV := range ctx.ServicePorts {
+		if getTargetPort == srvObjV["name"] {
+			log.Printf("::: INFO - Route - finding the service port: '%+v' whose match with the target port: '%+v' \n", srvObjV["name"], srvObjV["port"])
+			TargetPort = fmt.Sprint(srvObjV["port"])
+			break
+		}
+	}
+	if TargetPort == "" {
+		return fmt.Errorf("::: ERROR - Route - couldn't resolve targetPort %q to a Service port in this Template", getTargetPort)
+	}
+
+	// The Route's own host must be captured before SetUnstructuredContent
+	// overwrites k8sR below, otherwise the generated Ingress rule would
+	// apply to every host (and the connection-test hook would have nothing
+	// to wget against).
+	host, _, err := unstructured.NestedString(k8sR.Object, "spec", "host")
+	if err != nil {
+		return fmt.Errorf("::: ERROR - Route - failed to get the 'host' from the 'route' object: %v", err)
+	}
+	var hostJSON string
+	if host != "" {
+		hostJSON = `"host": "` + host + `",`
+	}
+
+	// ::: "Ingress" template without specify the ingressClassName aimed to use the default set on the cluster if any
+	// ::: referring to: https://kubernetes.io/docs/concepts/services-networking/ingress/#default-ingress-class
+	jsonIngressTemp := `{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind": "Ingress",
+		"metadata": {
+			"name": "ingress-` + k8sR.GetName() + `",
+			"annotations": {
+				"nginx.ingress.kubernetes.io/rewrite-target": "/"
+			}
+		},
+		"spec": {
+			"rules": [
+				{
+					` + hostJSON + `
+					"http": {
+						"paths": [
+							{
+								"path": "/",
+								"pathType": "Prefix",
+								"backend": {
+									"service": {
+										"name": "` + mTargetService["name"] + `",
+										"port": {
+											"number": ` + TargetPort + `
+										}
+									}
+								}
+							}
+						]
+					}
+				}
+			]
+		}
+	}`
+
+	var IngressObjData map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonIngressTemp), &IngressObjData); err != nil {
+		return fmt.Errorf("::: ERROR - Route - failed to build the Ingress for service %q: %v", mTargetService["name"], err)
+	}
+
+	k8sR.SetKind("Ingress")
+	k8sR.SetUnstructuredContent(IngressObjData)
+
+	return nil
+}
+
+// transformBuildConfig drops a BuildConfig from the converted chart: Helm
+// has no equivalent of an OpenShift build and re-triggering one is outside
+// what "helm install" should do, so the object is simply not emitted.
+func transformBuildConfig(k8sR *unstructured.Unstructured, ctx *ConvertContext) error {
+	log.Printf("::: INFO - BuildConfig - dropping object %s, BuildConfig has no Helm/Kubernetes equivalent", k8sR.GetName())
+	k8sR.SetUnstructuredContent(nil)
+	return nil
+}
+
+// transformImageStream emits no template for an ImageStream, since plain
+// Kubernetes has no ImageStream object to convert it into. Nothing from the
+// ImageStream is added to values.yaml: its image reference is not carried
+// over into the chart.
+func transformImageStream(k8sR *unstructured.Unstructured, ctx *ConvertContext) error {
+	log.Printf("::: INFO - ImageStream - dropping object %s, ImageStream has no Kubernetes equivalent", k8sR.GetName())
+	k8sR.SetUnstructuredContent(nil)
+	return nil
+}
+
+// rbacAPIGroup is the API group backing every RBAC role/subject reference
+// once a RoleBinding has been moved onto "rbac.authorization.k8s.io/v1".
+const rbacAPIGroup = "rbac.authorization.k8s.io"
+
+// transformRoleBinding bumps a RoleBinding's apiVersion from the OpenShift
+// "authorization.openshift.io/v1" group to the upstream
+// "rbac.authorization.k8s.io/v1" group. Unlike the OpenShift shape, the
+// upstream RoleBinding requires roleRef.apiGroup, and its User/Group
+// subjects require subjects[].apiGroup - both are filled in here since
+// OpenShift's RoleBinding leaves them implicit.
+func transformRoleBinding(k8sR *unstructured.Unstructured, ctx *ConvertContext) error {
+	log.Printf("::: INFO - RoleBinding - change the current apiVersion: %s ", k8sR.GetAPIVersion())
+	k8sR.SetAPIVersion("rbac.authorization.k8s.io/v1")
+
+	if err := unstructured.SetNestedField(k8sR.Object, rbacAPIGroup, "roleRef", "apiGroup"); err != nil {
+		return fmt.Errorf("::: ERROR - RoleBinding - failed to set 'roleRef.apiGroup': %v", err)
+	}
+
+	subjects, found, err := unstructured.NestedSlice(k8sR.Object, "subjects")
+	if err != nil {
+		return fmt.Errorf("::: ERROR - RoleBinding - failed to read 'subjects': %v", err)
+	}
+	if !found {
+		return nil
+	}
+
+	for _, s := range subjects {
+		subject, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch subject["kind"] {
+		case "User", "Group":
+			subject["apiGroup"] = rbacAPIGroup
+		}
+	}
+
+	if err := unstructured.SetNestedSlice(k8sR.Object, subjects, "subjects"); err != nil {
+		return fmt.Errorf("::: ERROR - RoleBinding - failed to set 'subjects': %v", err)
+	}
+
+	return nil
+}
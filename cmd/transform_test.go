@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTransformRoleBindingSetsAPIGroups(t *testing.T) {
+	rb := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "authorization.openshift.io/v1",
+		"kind":       "RoleBinding",
+		"metadata":   map[string]interface{}{"name": "edit-binding"},
+		"roleRef": map[string]interface{}{
+			"kind": "ClusterRole",
+			"name": "edit",
+		},
+		"subjects": []interface{}{
+			map[string]interface{}{"kind": "User", "name": "alice"},
+			map[string]interface{}{"kind": "ServiceAccount", "name": "default", "namespace": "myproject"},
+		},
+	}}
+
+	if err := transformRoleBinding(rb, NewConvertContext(nil)); err != nil {
+		t.Fatalf("transformRoleBinding returned an error: %v", err)
+	}
+
+	if got := rb.GetAPIVersion(); got != "rbac.authorization.k8s.io/v1" {
+		t.Errorf("apiVersion = %q, want rbac.authorization.k8s.io/v1", got)
+	}
+
+	apiGroup, _, _ := unstructured.NestedString(rb.Object, "roleRef", "apiGroup")
+	if apiGroup != rbacAPIGroup {
+		t.Errorf("roleRef.apiGroup = %q, want %q", apiGroup, rbacAPIGroup)
+	}
+
+	subjects, _, _ := unstructured.NestedSlice(rb.Object, "subjects")
+	user := subjects[0].(map[string]interface{})
+	if user["apiGroup"] != rbacAPIGroup {
+		t.Errorf("User subject apiGroup = %v, want %q", user["apiGroup"], rbacAPIGroup)
+	}
+	sa := subjects[1].(map[string]interface{})
+	if _, set := sa["apiGroup"]; set {
+		t.Errorf("ServiceAccount subject should not get an apiGroup, got %v", sa["apiGroup"])
+	}
+}
+
+func TestTransformRouteCarriesHostIntoIngress(t *testing.T) {
+	ctx := NewConvertContext(nil)
+	ctx.ServicePorts[0] = map[string]string{"name": "web", "port": "80", "targetPort": "8080"}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata":   map[string]interface{}{"name": "myapp"},
+		"spec": map[string]interface{}{
+			"host": "myapp.apps.example.com",
+			"to":   map[string]interface{}{"kind": "Service", "name": "myapp"},
+			"port": map[string]interface{}{"targetPort": "web"},
+		},
+	}}
+
+	if err := transformRoute(route, ctx); err != nil {
+		t.Fatalf("transformRoute returned an error: %v", err)
+	}
+
+	if got := route.GetKind(); got != "Ingress" {
+		t.Fatalf("kind = %q, want Ingress", got)
+	}
+
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if len(rules) != 1 {
+		t.Fatalf("expected exactly one rule, got %d", len(rules))
+	}
+	rule := rules[0].(map[string]interface{})
+	if rule["host"] != "myapp.apps.example.com" {
+		t.Errorf("rule host = %v, want myapp.apps.example.com", rule["host"])
+	}
+
+	paths, _, _ := unstructured.NestedSlice(rule["http"].(map[string]interface{}), "paths")
+	backendPort := paths[0].(map[string]interface{})["backend"].(map[string]interface{})["service"].(map[string]interface{})["port"].(map[string]interface{})["number"]
+	if fmt.Sprint(backendPort) != "80" {
+		t.Errorf("Ingress backend port = %v, want the Service's 'port' (80), not its 'targetPort'", backendPort)
+	}
+}
+
+func TestTransformRouteWithoutHostOmitsHostField(t *testing.T) {
+	ctx := NewConvertContext(nil)
+	ctx.ServicePorts[0] = map[string]string{"name": "web", "port": "80", "targetPort": "8080"}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata":   map[string]interface{}{"name": "myapp"},
+		"spec": map[string]interface{}{
+			"to":   map[string]interface{}{"kind": "Service", "name": "myapp"},
+			"port": map[string]interface{}{"targetPort": "web"},
+		},
+	}}
+
+	if err := transformRoute(route, ctx); err != nil {
+		t.Fatalf("transformRoute returned an error: %v", err)
+	}
+
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	rule := rules[0].(map[string]interface{})
+	if _, set := rule["host"]; set {
+		t.Errorf("rule should have no host field when the Route has none, got %v", rule["host"])
+	}
+}
+
+// TestTransformRouteUnresolvedTargetPortReturnsError ensures a Route whose
+// targetPort can't be matched against any known Service port produces a
+// clean error rather than the malformed-JSON crash that used to escape
+// through checkErr/log.Fatalf.
+func TestTransformRouteUnresolvedTargetPortReturnsError(t *testing.T) {
+	ctx := NewConvertContext(nil)
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata":   map[string]interface{}{"name": "myapp"},
+		"spec": map[string]interface{}{
+			"to":   map[string]interface{}{"kind": "Service", "name": "myapp"},
+			"port": map[string]interface{}{"targetPort": "nonexistent"},
+		},
+	}}
+
+	if err := transformRoute(route, ctx); err == nil {
+		t.Fatal("expected transformRoute to return an error for an unresolved targetPort, got nil")
+	}
+}